@@ -6,10 +6,14 @@ import (
 	"flag"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	registry "mcp-digitalocean/internal"
+	"mcp-digitalocean/internal/spaces"
 
 	"github.com/digitalocean/godo"
 	"github.com/mark3labs/mcp-go/server"
@@ -28,24 +32,33 @@ func main() {
 	serviceFlag := flag.String("services", os.Getenv("SERVICES"), "Comma-separated list of services to activate (e.g., apps,networking,droplets)")
 	tokenFlag := flag.String("digitalocean-api-token", os.Getenv("DIGITALOCEAN_API_TOKEN"), "DigitalOcean API token")
 	endpointFlag := flag.String("digitalocean-api-endpoint", os.Getenv("DIGITALOCEAN_API_ENDPOINT"), "DigitalOcean API endpoint")
+	transportFlag := flag.String("transport", envOrDefault("MCP_TRANSPORT", "stdio"), "Transport to serve over: stdio, sse, http")
+	listenAddrFlag := flag.String("listen-addr", envOrDefault("MCP_LISTEN_ADDR", ":8080"), "Address to listen on when transport is sse or http")
+	tlsCertFlag := flag.String("tls-cert", os.Getenv("MCP_TLS_CERT"), "TLS certificate file to serve sse/http over HTTPS")
+	tlsKeyFlag := flag.String("tls-key", os.Getenv("MCP_TLS_KEY"), "TLS key file to serve sse/http over HTTPS")
+	basePathFlag := flag.String("base-path", envOrDefault("MCP_BASE_PATH", "/mcp"), "Base path to mount the sse or http endpoints under")
+	authModeFlag := flag.String("auth-mode", os.Getenv("MCP_AUTH_MODE"), "Auth mode for network transports: bearer (shared secret) or per-request (DigitalOcean PAT per Authorization header)")
+	authSecretFlag := flag.String("auth-secret", os.Getenv("MCP_AUTH_SECRET"), "Shared secret required of callers when auth-mode is bearer")
+	spacesAccessIDFlag := flag.String("spaces-access-id", os.Getenv("SPACES_ACCESS_ID"), "DigitalOcean Spaces access key ID")
+	spacesSecretKeyFlag := flag.String("spaces-secret-key", os.Getenv("SPACES_SECRET_KEY"), "DigitalOcean Spaces secret access key")
+	spacesEndpointTemplateFlag := flag.String("spaces-endpoint-template", envOrDefault("SPACES_ENDPOINT_TEMPLATE", spaces.DefaultEndpointTemplate), "Go template for the Spaces endpoint, rendered per-region with {{.Region}}")
+	rpsFlag := flag.Float64("rps", 1, "Steady-state rate limit (requests/sec) applied to outgoing DigitalOcean API calls")
+	burstFlag := flag.Int("burst", 10, "Burst size allowed above the steady-state rate limit")
+	cacheFlag := flag.Bool("cache", false, "Cache GET responses from the DigitalOcean API in memory")
+	cacheTTLFlag := flag.Duration("cache-ttl", 30*time.Second, "Default TTL for cached GET responses when the server sends no Cache-Control")
+	cacheMaxBytesFlag := flag.Int("cache-max-bytes", 1<<20, "Largest response body eligible for caching, in bytes")
+	httpRetryMaxFlag := flag.Int("http-retry-max", envIntOrDefault("HTTP_RETRY_MAX", 4), "Maximum number of retries for a failed DigitalOcean API request")
+	httpRetryWaitMinFlag := flag.Float64("http-retry-wait-min", envFloatOrDefault("HTTP_RETRY_WAIT_MIN", 1), "Minimum backoff, in seconds, between retries")
+	httpRetryWaitMaxFlag := flag.Float64("http-retry-wait-max", envFloatOrDefault("HTTP_RETRY_WAIT_MAX", 30), "Maximum backoff, in seconds, between retries")
+	httpTimeoutFlag := flag.Duration("http-timeout", envDurationOrDefault("HTTP_TIMEOUT", 30*time.Second), "Per-request timeout for calls to the DigitalOcean API")
+	userAgentSuffixFlag := flag.String("user-agent-suffix", os.Getenv("USER_AGENT_SUFFIX"), "Extra text appended to the User-Agent sent to the DigitalOcean API")
+	tokenFileFlag := flag.String("token-file", os.Getenv("DIGITALOCEAN_API_TOKEN_FILE"), "Read the DigitalOcean API token from this file, reloading it whenever it changes")
+	tokenCommandFlag := flag.String("token-command", os.Getenv("DIGITALOCEAN_API_TOKEN_COMMAND"), "Shell command whose stdout is used as the DigitalOcean API token, re-run when the cached token expires")
 	flag.Parse()
 
-	var level slog.Level
-	switch strings.ToLower(*logLevelFlag) {
-	case "debug":
-		level = slog.LevelDebug
-	case "info":
-		level = slog.LevelInfo
-	case "warn", "warning":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
-	default:
-		level = slog.LevelInfo
-	}
-
-	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
-	token := *tokenFlag
+	var levelVar slog.LevelVar
+	levelVar.Set(parseLogLevel(*logLevelFlag))
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: &levelVar}))
 
 	endpoint := *endpointFlag
 	if endpoint == "" {
@@ -57,31 +70,77 @@ func main() {
 		services = strings.Split(*serviceFlag, ",")
 	}
 
-	// Create client - if no token provided, create a client that will fail on API calls
-	var client *godo.Client
-	var err error
-	if token == "" {
-		logger.Warn("DigitalOcean API token not provided. Server will start but API calls will fail until token is available. Use --digitalocean-api-token flag or set DIGITALOCEAN_API_TOKEN environment variable")
-		// Create a client with empty token - this will allow server to start but API calls will fail
-		client, err = newGodoClientWithTokenAndEndpoint(context.Background(), "", endpoint)
-	} else {
-		client, err = newGodoClientWithTokenAndEndpoint(context.Background(), token, endpoint)
+	if *tokenFlag == "" && *tokenFileFlag == "" && *tokenCommandFlag == "" {
+		logger.Warn("DigitalOcean API token not provided. Server will start but API calls will fail until token is available. Use --digitalocean-api-token, --token-file, or --token-command")
 	}
-	
+	tokenSource, err := newRotatingTokenSource(context.Background(), logger, tokenSourceConfig{
+		Token:        *tokenFlag,
+		TokenFile:    *tokenFileFlag,
+		TokenCommand: *tokenCommandFlag,
+	})
+	if err != nil {
+		logger.Error("Failed to set up DigitalOcean API token source: " + err.Error())
+		os.Exit(1)
+	}
+
+	clientMetrics := &metrics{}
+	clientOpts := godoClientOptions{
+		rps:             *rpsFlag,
+		burst:           *burstFlag,
+		cache:           *cacheFlag,
+		cacheTTL:        *cacheTTLFlag,
+		cacheMaxBytes:   *cacheMaxBytesFlag,
+		metrics:         clientMetrics,
+		retryMax:        *httpRetryMaxFlag,
+		retryWaitMin:    *httpRetryWaitMinFlag,
+		retryWaitMax:    *httpRetryWaitMaxFlag,
+		timeout:         *httpTimeoutFlag,
+		userAgentSuffix: *userAgentSuffixFlag,
+	}
+
+	client, err := newGodoClientWithTokenAndEndpoint(context.Background(), tokenSource, endpoint, clientOpts)
 	if err != nil {
 		logger.Error("Failed to create DigitalOcean client: " + err.Error())
 		os.Exit(1)
 	}
 
+	var spacesClient *spaces.Client
+	if *spacesAccessIDFlag == "" || *spacesSecretKeyFlag == "" {
+		logger.Warn("DigitalOcean Spaces credentials not provided, spaces tools will be unavailable. Use --spaces-access-id and --spaces-secret-key or the matching environment variables")
+	} else {
+		spacesClient, err = spaces.NewClient(spaces.Config{
+			AccessID:         *spacesAccessIDFlag,
+			SecretKey:        *spacesSecretKeyFlag,
+			EndpointTemplate: *spacesEndpointTemplateFlag,
+		})
+		if err != nil {
+			logger.Error("Failed to create Spaces client: " + err.Error())
+			os.Exit(1)
+		}
+	}
+
 	s := server.NewMCPServer(mcpName, mcpVersion)
-	err = registry.Register(logger, s, client, services...)
+	err = registry.Register(logger, s, registry.Clients{DO: client, Spaces: spacesClient}, services...)
 	if err != nil {
 		logger.Error("Failed to register tools: " + err.Error())
 		os.Exit(1)
 	}
 
-	logger.Debug("starting MCP server", "name", mcpName, "version", mcpVersion)
-	err = server.ServeStdio(s)
+	cfg := transportConfig{
+		mode:       strings.ToLower(*transportFlag),
+		listenAddr: *listenAddrFlag,
+		tlsCert:    *tlsCertFlag,
+		tlsKey:     *tlsKeyFlag,
+		basePath:   *basePathFlag,
+		authMode:   strings.ToLower(*authModeFlag),
+		authSecret: *authSecretFlag,
+		metrics:    clientMetrics,
+	}
+
+	handleSIGHUP(logger, &levelVar, s, registry.Clients{DO: client, Spaces: spacesClient}, services, tokenSource, *tokenFileFlag, *tokenCommandFlag)
+
+	logger.Debug("starting MCP server", "name", mcpName, "version", mcpVersion, "transport", cfg.mode)
+	err = serve(context.Background(), logger, s, cfg)
 	if err != nil {
 		// if context cancelled or sigterm then shutdown gracefully
 		if errors.Is(err, context.Canceled) {
@@ -94,18 +153,75 @@ func main() {
 	}
 }
 
+// envOrDefault returns the value of the named environment variable, or def if unset.
+func envOrDefault(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// envIntOrDefault returns the named environment variable parsed as an int, or def if unset or invalid.
+func envIntOrDefault(name string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// envFloatOrDefault returns the named environment variable parsed as a float64, or def if unset or invalid.
+func envFloatOrDefault(name string, def float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// envDurationOrDefault returns the named environment variable parsed as a time.Duration, or def if unset or invalid.
+func envDurationOrDefault(name string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// godoClientOptions configures the rate limiting, caching, retry/backoff,
+// timeout and user-agent behavior of the godo HTTP client.
+type godoClientOptions struct {
+	rps           float64
+	burst         int
+	cache         bool
+	cacheTTL      time.Duration
+	cacheMaxBytes int
+	metrics       *metrics
+
+	retryMax        int
+	retryWaitMin    float64
+	retryWaitMax    float64
+	timeout         time.Duration
+	userAgentSuffix string
+}
+
 // newGodoClientWithTokenAndEndpoint initializes a new godo client with a custom user agent and endpoint.
-func newGodoClientWithTokenAndEndpoint(ctx context.Context, token string, endpoint string) (*godo.Client, error) {
-	cleanToken := strings.Trim(strings.TrimSpace(token), "'")
-	
-	// Create oauth client - even with empty token to allow server startup
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: cleanToken})
-	oauthClient := oauth2.NewClient(ctx, ts)
+func newGodoClientWithTokenAndEndpoint(ctx context.Context, ts oauth2.TokenSource, endpoint string, opts godoClientOptions) (*godo.Client, error) {
+	var base http.RoundTripper = newUserAgentTransport(newContextTokenTransport(http.DefaultTransport), opts.userAgentSuffix)
+	base = newRateLimitedTransport(base, opts.rps, opts.burst, opts.metrics)
+	if opts.cache {
+		// Cache wraps the limiter so a hit is served without waiting on
+		// Wait(ctx) or counting towards godo_requests_total.
+		base = newCachingTransport(base, opts.cacheTTL, opts.cacheMaxBytes, opts.metrics)
+	}
+
+	oauthClient := oauth2.NewClient(context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: base}), ts)
+	oauthClient.Timeout = opts.timeout
 
 	retry := godo.RetryConfig{
-		RetryMax:     4,
-		RetryWaitMin: godo.PtrTo(float64(1)),
-		RetryWaitMax: godo.PtrTo(float64(30)),
+		RetryMax:     opts.retryMax,
+		RetryWaitMin: godo.PtrTo(opts.retryWaitMin),
+		RetryWaitMax: godo.PtrTo(opts.retryWaitMax),
 	}
 
 	return godo.New(oauthClient,
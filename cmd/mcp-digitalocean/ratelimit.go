@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// metrics tracks the Prometheus-style counters exposed on /metrics when the
+// server is running over a network transport.
+type metrics struct {
+	requestsTotal      uint64
+	rateLimitedSeconds uint64 // accumulated as time.Duration nanoseconds
+	cacheHitsTotal     uint64
+}
+
+func (m *metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintln(w, "# HELP godo_requests_total Total requests issued to the DigitalOcean API.")
+	fmt.Fprintln(w, "# TYPE godo_requests_total counter")
+	fmt.Fprintf(w, "godo_requests_total %d\n", atomic.LoadUint64(&m.requestsTotal))
+	fmt.Fprintln(w, "# HELP godo_rate_limited_seconds Cumulative time spent blocked on the client-side rate limiter.")
+	fmt.Fprintln(w, "# TYPE godo_rate_limited_seconds counter")
+	fmt.Fprintf(w, "godo_rate_limited_seconds %f\n", time.Duration(atomic.LoadUint64(&m.rateLimitedSeconds)).Seconds())
+	fmt.Fprintln(w, "# HELP godo_cache_hits_total Total GET requests served from the in-memory cache.")
+	fmt.Fprintln(w, "# TYPE godo_cache_hits_total counter")
+	fmt.Fprintf(w, "godo_cache_hits_total %d\n", atomic.LoadUint64(&m.cacheHitsTotal))
+}
+
+// contextTokenTransport overrides the outgoing Authorization header with the
+// PAT stashed on the request's context by authContextFunc, so that in
+// --auth-mode=per-request each MCP caller's own DigitalOcean token is used
+// instead of the server's startup token. It is a no-op when the context
+// carries no per-request token.
+type contextTokenTransport struct {
+	base http.RoundTripper
+}
+
+func newContextTokenTransport(base http.RoundTripper) *contextTokenTransport {
+	return &contextTokenTransport{base: base}
+}
+
+func (t *contextTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, ok := tokenFromContext(req.Context())
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+// rateLimitedTransport blocks each request on a token-bucket limiter before
+// handing it to the wrapped transport, so MCP tool calls stay under
+// DigitalOcean's account-wide rate limits.
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+	metrics *metrics
+}
+
+func newRateLimitedTransport(base http.RoundTripper, rps float64, burst int, m *metrics) *rateLimitedTransport {
+	return &rateLimitedTransport{base: base, limiter: rate.NewLimiter(rate.Limit(rps), burst), metrics: m}
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	if waited := time.Since(start); waited > 0 {
+		atomic.AddUint64(&t.metrics.rateLimitedSeconds, uint64(waited))
+	}
+	atomic.AddUint64(&t.metrics.requestsTotal, 1)
+	return t.base.RoundTrip(req)
+}
+
+// cacheEntry holds a cached GET response body plus the validators needed to
+// decide whether it can still be served or must be revalidated.
+type cacheEntry struct {
+	status    int
+	header    http.Header
+	body      []byte
+	etag      string
+	expiresAt time.Time
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		StatusCode: e.status,
+		Status:     http.StatusText(e.status),
+		Header:     e.header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(e.body)),
+		Request:    req,
+	}
+}
+
+// cacheTotalBudgetFactor bounds the cache's total memory use as a multiple of
+// maxBytes (the largest single response it will store), so --cache-max-bytes
+// also limits overall process memory rather than just per-entry size.
+const cacheTotalBudgetFactor = 64
+
+// cachingTransport serves repeated GET requests (list/read calls an MCP
+// conversation tends to re-issue) from memory, honoring Cache-Control and
+// ETag/If-None-Match like a conditional HTTP cache.
+type cachingTransport struct {
+	base     http.RoundTripper
+	ttl      time.Duration
+	maxBytes int
+	maxTotal int
+	metrics  *metrics
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	size    int
+}
+
+func newCachingTransport(base http.RoundTripper, ttl time.Duration, maxBytes int, m *metrics) *cachingTransport {
+	return &cachingTransport{
+		base:     base,
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		maxTotal: maxBytes * cacheTotalBudgetFactor,
+		metrics:  m,
+		entries:  make(map[string]*cacheEntry),
+	}
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	if token, ok := tokenFromContext(req.Context()); ok {
+		// Per-request auth means the same URL can return different data for
+		// different callers; key the cache on the caller's token too so a
+		// hit never leaks one user's response to another.
+		key = token + " " + key
+	}
+
+	t.mu.Lock()
+	entry, ok := t.entries[key]
+	t.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		atomic.AddUint64(&t.metrics.cacheHitsTotal, 1)
+		return entry.response(req), nil
+	}
+
+	if ok && entry.etag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		atomic.AddUint64(&t.metrics.cacheHitsTotal, 1)
+		resp.Body.Close()
+		return t.refresh(key, entry, req)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		t.store(key, resp)
+	}
+
+	return resp, nil
+}
+
+// refresh extends entry's lifetime after a 304 response revalidated it,
+// returning the still-fresh cached body. The update happens under t.mu
+// since entry is shared with concurrent readers/writers of the same key.
+func (t *cachingTransport) refresh(key string, entry *cacheEntry, req *http.Request) (*http.Response, error) {
+	refreshed := *entry
+	refreshed.expiresAt = time.Now().Add(t.ttl)
+
+	t.mu.Lock()
+	t.entries[key] = &refreshed
+	t.mu.Unlock()
+
+	return refreshed.response(req), nil
+}
+
+func (t *cachingTransport) store(key string, resp *http.Response) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(body) > t.maxBytes {
+		return
+	}
+
+	cc := resp.Header.Get("Cache-Control")
+	ttl := t.ttl
+	if d, ok := maxAgeFrom(cc); ok {
+		ttl = d
+	}
+	for _, part := range strings.Split(cc, ",") {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "no-store":
+			return
+		case "no-cache":
+			// Stored, but revalidated before every reuse.
+			ttl = 0
+		}
+	}
+
+	entry := &cacheEntry{
+		status:    resp.StatusCode,
+		header:    resp.Header.Clone(),
+		body:      body,
+		etag:      resp.Header.Get("ETag"),
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if old, ok := t.entries[key]; ok {
+		t.size -= len(old.body)
+	}
+	t.entries[key] = entry
+	t.size += len(body)
+	t.evictLocked()
+}
+
+// evictLocked drops expired entries first, then arbitrary ones, until the
+// cache fits within t.maxTotal. Must be called with t.mu held.
+func (t *cachingTransport) evictLocked() {
+	if t.size <= t.maxTotal {
+		return
+	}
+
+	now := time.Now()
+	for key, e := range t.entries {
+		if now.After(e.expiresAt) {
+			t.size -= len(e.body)
+			delete(t.entries, key)
+		}
+	}
+
+	for key, e := range t.entries {
+		if t.size <= t.maxTotal {
+			break
+		}
+		t.size -= len(e.body)
+		delete(t.entries, key)
+	}
+}
+
+// maxAgeFrom extracts max-age=N from a Cache-Control header value.
+func maxAgeFrom(cc string) (time.Duration, bool) {
+	for _, part := range strings.Split(cc, ",") {
+		part = strings.TrimSpace(part)
+		if v, ok := strings.CutPrefix(part, "max-age="); ok {
+			if secs, err := strconv.Atoi(v); err == nil {
+				return time.Duration(secs) * time.Second, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubTransport serves canned responses in order, one per call to RoundTrip.
+type stubTransport struct {
+	responses []*http.Response
+	calls     int
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := s.responses[s.calls]
+	s.calls++
+	resp.Request = req
+	return resp, nil
+}
+
+func newResponse(status int, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: header}
+}
+
+func TestRateLimitedTransport(t *testing.T) {
+	m := &metrics{}
+	base := &stubTransport{responses: []*http.Response{newResponse(http.StatusOK, "ok", nil)}}
+	// A generous limiter so the test doesn't block; we're checking accounting,
+	// not the throttling itself.
+	rt := newRateLimitedTransport(base, 1000, 10, m)
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.digitalocean.com/v2/droplets", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := atomic.LoadUint64(&m.requestsTotal); got != 1 {
+		t.Errorf("requestsTotal = %d, want 1", got)
+	}
+}
+
+func TestRateLimitedTransport_BlocksBeyondBurst(t *testing.T) {
+	m := &metrics{}
+	base := &stubTransport{responses: []*http.Response{
+		newResponse(http.StatusOK, "1", nil),
+		newResponse(http.StatusOK, "2", nil),
+	}}
+	// rps=1, burst=1: the second call within the same instant must wait
+	// roughly 1s for a new token.
+	rt := newRateLimitedTransport(base, 1, 1, m)
+	req := httptest.NewRequest(http.MethodGet, "https://api.digitalocean.com/v2/droplets", nil)
+
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("first RoundTrip() error = %v", err)
+	}
+
+	start := time.Now()
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("second RoundTrip() error = %v", err)
+	}
+	if waited := time.Since(start); waited < 500*time.Millisecond {
+		t.Errorf("second call returned after %v, want it to have waited on the limiter", waited)
+	}
+	if got := atomic.LoadUint64(&m.rateLimitedSeconds); got == 0 {
+		t.Error("rateLimitedSeconds = 0, want it to record the wait")
+	}
+}
+
+func TestCachingTransport_MissThenHit(t *testing.T) {
+	m := &metrics{}
+	base := &stubTransport{responses: []*http.Response{newResponse(http.StatusOK, "cached body", nil)}}
+	ct := newCachingTransport(base, time.Minute, 1<<20, m)
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.digitalocean.com/v2/droplets", nil)
+
+	resp1, err := ct.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("first RoundTrip() error = %v", err)
+	}
+	body1, _ := io.ReadAll(resp1.Body)
+	if string(body1) != "cached body" {
+		t.Fatalf("first body = %q, want %q", body1, "cached body")
+	}
+
+	resp2, err := ct.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("second RoundTrip() error = %v", err)
+	}
+	body2, _ := io.ReadAll(resp2.Body)
+	if string(body2) != "cached body" {
+		t.Errorf("second body = %q, want %q", body2, "cached body")
+	}
+	if base.calls != 1 {
+		t.Errorf("base.calls = %d, want 1 (second call should be served from cache)", base.calls)
+	}
+	if got := atomic.LoadUint64(&m.cacheHitsTotal); got != 1 {
+		t.Errorf("cacheHitsTotal = %d, want 1", got)
+	}
+}
+
+func TestCachingTransport_ExpiresAfterTTL(t *testing.T) {
+	m := &metrics{}
+	base := &stubTransport{responses: []*http.Response{
+		newResponse(http.StatusOK, "first", nil),
+		newResponse(http.StatusOK, "second", nil),
+	}}
+	ct := newCachingTransport(base, time.Millisecond, 1<<20, m)
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.digitalocean.com/v2/droplets", nil)
+	if _, err := ct.RoundTrip(req); err != nil {
+		t.Fatalf("first RoundTrip() error = %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	resp, err := ct.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("second RoundTrip() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "second" {
+		t.Errorf("body = %q, want %q (expired entry should be refetched)", body, "second")
+	}
+	if base.calls != 2 {
+		t.Errorf("base.calls = %d, want 2", base.calls)
+	}
+}
+
+func TestCachingTransport_RevalidatesOn304(t *testing.T) {
+	m := &metrics{}
+	base := &stubTransport{responses: []*http.Response{
+		newResponse(http.StatusOK, "etagged body", http.Header{"Etag": []string{`"v1"`}}),
+		newResponse(http.StatusNotModified, "", nil),
+	}}
+	ct := newCachingTransport(base, time.Nanosecond, 1<<20, m)
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.digitalocean.com/v2/droplets", nil)
+	if _, err := ct.RoundTrip(req); err != nil {
+		t.Fatalf("first RoundTrip() error = %v", err)
+	}
+
+	time.Sleep(time.Millisecond) // let the TTL lapse so the next call revalidates
+
+	resp, err := ct.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("second RoundTrip() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "etagged body" {
+		t.Errorf("body = %q, want the cached body to be served on a 304", body)
+	}
+	if got := atomic.LoadUint64(&m.cacheHitsTotal); got != 1 {
+		t.Errorf("cacheHitsTotal = %d, want 1", got)
+	}
+}
+
+func TestCachingTransport_KeyedByPerRequestToken(t *testing.T) {
+	m := &metrics{}
+	base := &stubTransport{responses: []*http.Response{
+		newResponse(http.StatusOK, "alice's data", nil),
+		newResponse(http.StatusOK, "bob's data", nil),
+	}}
+	ct := newCachingTransport(base, time.Minute, 1<<20, m)
+
+	url := "https://api.digitalocean.com/v2/droplets"
+	aliceReq := httptest.NewRequest(http.MethodGet, url, nil)
+	aliceReq = aliceReq.WithContext(context.WithValue(aliceReq.Context(), doTokenContextKey{}, "alice-pat"))
+	bobReq := httptest.NewRequest(http.MethodGet, url, nil)
+	bobReq = bobReq.WithContext(context.WithValue(bobReq.Context(), doTokenContextKey{}, "bob-pat"))
+
+	respA, err := ct.RoundTrip(aliceReq)
+	if err != nil {
+		t.Fatalf("alice RoundTrip() error = %v", err)
+	}
+	bodyA, _ := io.ReadAll(respA.Body)
+	if string(bodyA) != "alice's data" {
+		t.Fatalf("alice body = %q, want %q", bodyA, "alice's data")
+	}
+
+	respB, err := ct.RoundTrip(bobReq)
+	if err != nil {
+		t.Fatalf("bob RoundTrip() error = %v", err)
+	}
+	bodyB, _ := io.ReadAll(respB.Body)
+	if string(bodyB) != "bob's data" {
+		t.Errorf("bob body = %q, want %q (should not be served alice's cached entry)", bodyB, "bob's data")
+	}
+	if base.calls != 2 {
+		t.Errorf("base.calls = %d, want 2 (per-user cache keys should both miss)", base.calls)
+	}
+}
+
+func TestCachingTransport_EvictsOverBudget(t *testing.T) {
+	m := &metrics{}
+	responses := make([]*http.Response, 0, 10)
+	for i := 0; i < 10; i++ {
+		responses = append(responses, newResponse(http.StatusOK, strings.Repeat("x", 100), nil))
+	}
+	base := &stubTransport{responses: responses}
+	// maxBytes=100 so each entry fits individually. The default total budget
+	// (cacheTotalBudgetFactor*100) would hold all 10 entries, so shrink it to
+	// force eviction with more distinct keys than the budget can hold.
+	ct := newCachingTransport(base, time.Minute, 100, m)
+	ct.maxTotal = 250 // small budget: at most ~2 entries fit
+
+	for i := 0; i < 10; i++ {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://api.digitalocean.com/v2/droplets?page=%d", i), nil)
+		if _, err := ct.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+	}
+
+	ct.mu.Lock()
+	size := ct.size
+	ct.mu.Unlock()
+
+	if size > ct.maxTotal {
+		t.Errorf("cache size = %d, want <= maxTotal (%d)", size, ct.maxTotal)
+	}
+}
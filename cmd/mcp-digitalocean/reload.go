@@ -0,0 +1,92 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	registry "mcp-digitalocean/internal"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// handleSIGHUP installs a SIGHUP handler that reloads what it safely can
+// without dropping the current MCP session, re-reading it from the
+// environment rather than from the command-line flags: the log level
+// (LOG_LEVEL), the static DigitalOcean API token (DIGITALOCEAN_API_TOKEN,
+// when neither --token-file nor --token-command is in use), and any newly
+// added entries in SERVICES. Tool groups already registered are never
+// unregistered, since mcp-go has no API for that.
+func handleSIGHUP(
+	logger *slog.Logger,
+	levelVar *slog.LevelVar,
+	s *server.MCPServer,
+	clients registry.Clients,
+	initialServices []string,
+	tokenSource *rotatingTokenSource,
+	tokenFile, tokenCommand string,
+) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	var mu sync.Mutex
+	active := make(map[string]bool, len(initialServices))
+	for _, svc := range initialServices {
+		active[svc] = true
+	}
+
+	go func() {
+		for range sighup {
+			logger.Info("received SIGHUP, reloading log level, services, and token")
+
+			levelVar.Set(parseLogLevel(os.Getenv("LOG_LEVEL")))
+
+			if tokenFile == "" && tokenCommand == "" {
+				if token := strings.Trim(strings.TrimSpace(os.Getenv("DIGITALOCEAN_API_TOKEN")), "'"); token != "" {
+					tokenSource.reset(staticRawSource{token: token})
+					logger.Info("reloaded DigitalOcean API token from environment")
+				}
+			}
+
+			mu.Lock()
+			var added []string
+			for _, svc := range strings.Split(os.Getenv("SERVICES"), ",") {
+				svc = strings.TrimSpace(svc)
+				if svc == "" || active[svc] {
+					continue
+				}
+				active[svc] = true
+				added = append(added, svc)
+			}
+			mu.Unlock()
+
+			if len(added) > 0 {
+				if err := registry.Register(logger, s, clients, added...); err != nil {
+					logger.Error("Failed to register newly added services: " + err.Error())
+				} else {
+					logger.Info("registered newly added services", "services", added)
+				}
+			}
+		}
+	}()
+}
+
+// parseLogLevel maps the --log-level/LOG_LEVEL string to a slog.Level,
+// defaulting to info for an empty or unrecognized value.
+func parseLogLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
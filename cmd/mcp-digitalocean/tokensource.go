@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/oauth2"
+)
+
+// commandTokenTTL bounds how long a token minted by --token-command is
+// reused before the command is invoked again.
+const commandTokenTTL = 5 * time.Minute
+
+// rotatingTokenSource is the oauth2.TokenSource installed on the godo HTTP
+// client. It delegates to whatever underlying source is currently active,
+// so a token file change, SIGHUP, or expired command-sourced token can all
+// force a fresh credential without restarting the server.
+type rotatingTokenSource struct {
+	mu  sync.RWMutex
+	cur oauth2.TokenSource
+}
+
+func (r *rotatingTokenSource) Token() (*oauth2.Token, error) {
+	r.mu.RLock()
+	cur := r.cur
+	r.mu.RUnlock()
+	return cur.Token()
+}
+
+// reset installs raw as the new underlying source, discarding any cached
+// token so the next call fetches immediately.
+func (r *rotatingTokenSource) reset(raw oauth2.TokenSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cur = oauth2.ReuseTokenSource(nil, raw)
+}
+
+// staticRawSource returns the same token forever; used when no rotation
+// mechanism is configured.
+type staticRawSource struct {
+	token string
+}
+
+func (s staticRawSource) Token() (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: s.token}, nil
+}
+
+// fileRawSource reads the token from a file. It is also re-read whenever
+// the fsnotify watcher installed by watchTokenFile observes a write, but
+// still carries a TTL as a fallback in case events are missed.
+type fileRawSource struct {
+	path string
+}
+
+func (s fileRawSource) Token() (*oauth2.Token, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("tokensource: reading %s: %w", s.path, err)
+	}
+	token := strings.Trim(strings.TrimSpace(string(data)), "'")
+	if token == "" {
+		return nil, fmt.Errorf("tokensource: %s is empty", s.path)
+	}
+	return &oauth2.Token{AccessToken: token, Expiry: time.Now().Add(commandTokenTTL)}, nil
+}
+
+// commandRawSource runs an external command and takes its trimmed stdout as
+// the token. It is invoked by ReuseTokenSource whenever the previously
+// returned token's Expiry has passed.
+type commandRawSource struct {
+	command string
+}
+
+func (s commandRawSource) Token() (*oauth2.Token, error) {
+	out, err := exec.Command("sh", "-c", s.command).Output()
+	if err != nil {
+		return nil, fmt.Errorf("tokensource: running token-command: %w", err)
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return nil, fmt.Errorf("tokensource: token-command produced no output")
+	}
+	return &oauth2.Token{AccessToken: token, Expiry: time.Now().Add(commandTokenTTL)}, nil
+}
+
+// tokenSourceConfig picks which rotation mechanism backs the token source.
+// At most one of TokenFile/TokenCommand is expected to be set; Token is the
+// static fallback used when neither is (refreshed on SIGHUP, see main).
+type tokenSourceConfig struct {
+	Token        string
+	TokenFile    string
+	TokenCommand string
+}
+
+// rawSourceFor builds the oauth2.TokenSource matching cfg's active mode.
+func rawSourceFor(cfg tokenSourceConfig) oauth2.TokenSource {
+	switch {
+	case cfg.TokenFile != "":
+		return fileRawSource{path: cfg.TokenFile}
+	case cfg.TokenCommand != "":
+		return commandRawSource{command: cfg.TokenCommand}
+	default:
+		return staticRawSource{token: strings.Trim(strings.TrimSpace(cfg.Token), "'")}
+	}
+}
+
+// newRotatingTokenSource builds a rotatingTokenSource for cfg and, when
+// cfg.TokenFile is set, starts an fsnotify watcher that forces a refresh on
+// every write so file-based rotation is picked up immediately rather than
+// waiting out commandTokenTTL.
+func newRotatingTokenSource(ctx context.Context, logger *slog.Logger, cfg tokenSourceConfig) (*rotatingTokenSource, error) {
+	rts := &rotatingTokenSource{}
+	rts.reset(rawSourceFor(cfg))
+
+	if cfg.TokenFile != "" {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return nil, fmt.Errorf("tokensource: creating file watcher: %w", err)
+		}
+		if err := watcher.Add(cfg.TokenFile); err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("tokensource: watching %s: %w", cfg.TokenFile, err)
+		}
+
+		go func() {
+			defer watcher.Close()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event, ok := <-watcher.Events:
+					if !ok {
+						return
+					}
+					if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+						logger.Info("token file changed, reloading DigitalOcean API token", "path", cfg.TokenFile)
+						rts.reset(fileRawSource{path: cfg.TokenFile})
+					}
+				case err, ok := <-watcher.Errors:
+					if !ok {
+						return
+					}
+					logger.Error("token file watcher error: " + err.Error())
+				}
+			}
+		}()
+	}
+
+	return rts, nil
+}
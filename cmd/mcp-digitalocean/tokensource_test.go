@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestFileRawSource(t *testing.T) {
+	tests := []struct {
+		name      string
+		contents  string
+		wantToken string
+		wantErr   bool
+	}{
+		{"plain token", "my-token", "my-token", false},
+		{"trims surrounding whitespace", "  my-token\n", "my-token", false},
+		{"trims surrounding quotes", "'my-token'", "my-token", false},
+		{"empty file is an error", "", "", true},
+		{"whitespace-only file is an error", "   \n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "token")
+			if err := os.WriteFile(path, []byte(tt.contents), 0o600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			tok, err := (fileRawSource{path: path}).Token()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Token() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+			if tok.AccessToken != tt.wantToken {
+				t.Errorf("AccessToken = %q, want %q", tok.AccessToken, tt.wantToken)
+			}
+			if tok.Expiry.IsZero() {
+				t.Error("Expiry is zero, want a future expiry so ReuseTokenSource re-reads the file")
+			}
+		})
+	}
+
+	t.Run("missing file is an error", func(t *testing.T) {
+		if _, err := (fileRawSource{path: filepath.Join(t.TempDir(), "missing")}).Token(); err == nil {
+			t.Fatal("Token() error = nil, want an error")
+		}
+	})
+}
+
+func TestCommandRawSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr bool
+	}{
+		{"trims trailing newline", "echo my-token", "my-token", false},
+		{"non-zero exit is an error", "exit 1", "", true},
+		{"empty stdout is an error", "echo -n ''", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tok, err := (commandRawSource{command: tt.command}).Token()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Token() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+			if tok.AccessToken != tt.want {
+				t.Errorf("AccessToken = %q, want %q", tok.AccessToken, tt.want)
+			}
+		})
+	}
+}
+
+func TestRawSourceFor(t *testing.T) {
+	t.Run("token file takes precedence", func(t *testing.T) {
+		src := rawSourceFor(tokenSourceConfig{Token: "static", TokenFile: "/some/path", TokenCommand: "echo x"})
+		if _, ok := src.(fileRawSource); !ok {
+			t.Errorf("rawSourceFor() = %T, want fileRawSource", src)
+		}
+	})
+
+	t.Run("token command is used when no file is set", func(t *testing.T) {
+		src := rawSourceFor(tokenSourceConfig{Token: "static", TokenCommand: "echo x"})
+		if _, ok := src.(commandRawSource); !ok {
+			t.Errorf("rawSourceFor() = %T, want commandRawSource", src)
+		}
+	})
+
+	t.Run("falls back to the static token, trimmed", func(t *testing.T) {
+		src := rawSourceFor(tokenSourceConfig{Token: "  'static-token'  "})
+		tok, err := src.Token()
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+		if tok.AccessToken != "static-token" {
+			t.Errorf("AccessToken = %q, want %q", tok.AccessToken, "static-token")
+		}
+	})
+}
+
+// countingSource records how many times Token was called, so tests can
+// assert that rotatingTokenSource actually reuses the cached token between
+// calls instead of re-fetching every time.
+type countingSource struct {
+	calls int
+	token *oauth2.Token
+}
+
+func (c *countingSource) Token() (*oauth2.Token, error) {
+	c.calls++
+	return c.token, nil
+}
+
+func TestRotatingTokenSource_ReusesUntilReset(t *testing.T) {
+	rts := &rotatingTokenSource{}
+	cs := &countingSource{token: &oauth2.Token{AccessToken: "first"}}
+	rts.reset(cs)
+
+	for i := 0; i < 3; i++ {
+		tok, err := rts.Token()
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+		if tok.AccessToken != "first" {
+			t.Errorf("AccessToken = %q, want %q", tok.AccessToken, "first")
+		}
+	}
+	if cs.calls != 1 {
+		t.Errorf("underlying source called %d times, want 1 (ReuseTokenSource should cache)", cs.calls)
+	}
+
+	cs2 := &countingSource{token: &oauth2.Token{AccessToken: "second"}}
+	rts.reset(cs2)
+
+	tok, err := rts.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if tok.AccessToken != "second" {
+		t.Errorf("AccessToken = %q, want %q (reset should force a refetch)", tok.AccessToken, "second")
+	}
+	if cs2.calls != 1 {
+		t.Errorf("new source called %d times, want 1", cs2.calls)
+	}
+}
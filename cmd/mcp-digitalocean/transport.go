@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// transportConfig holds the flags that control how the MCP server is exposed.
+type transportConfig struct {
+	mode       string // stdio, sse, http
+	listenAddr string
+	tlsCert    string
+	tlsKey     string
+	basePath   string
+	authMode   string // bearer, per-request
+	authSecret string
+	metrics    *metrics
+}
+
+// doTokenContextKey is the context key used to carry a per-request DigitalOcean
+// PAT extracted from the Authorization header through to tool handlers.
+type doTokenContextKey struct{}
+
+// tokenFromContext returns the DigitalOcean PAT stashed in ctx by the
+// per-request auth middleware, if any. It is read by contextTokenTransport
+// to scope the outgoing godo request to the calling user's own PAT instead
+// of the server's startup token.
+func tokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(doTokenContextKey{}).(string)
+	return token, ok && token != ""
+}
+
+// serve starts the MCP server using the transport described by cfg, blocking
+// until the server stops or returns an error.
+func serve(ctx context.Context, logger *slog.Logger, s *server.MCPServer, cfg transportConfig) error {
+	switch cfg.mode {
+	case "", "stdio":
+		logger.Debug("starting MCP server over stdio", "name", mcpName, "version", mcpVersion)
+		return server.ServeStdio(s)
+	case "sse":
+		logger.Debug("starting MCP server over SSE", "addr", cfg.listenAddr, "base-path", cfg.basePath)
+		sseServer := server.NewSSEServer(s,
+			server.WithBasePath(cfg.basePath),
+			server.WithSSEContextFunc(authContextFunc(cfg)),
+		)
+		return listenAndServe(sseServer, cfg)
+	case "http":
+		logger.Debug("starting MCP server over streamable HTTP", "addr", cfg.listenAddr, "base-path", cfg.basePath)
+		httpServer := server.NewStreamableHTTPServer(s,
+			server.WithEndpointPath(cfg.basePath),
+			server.WithHTTPContextFunc(authContextFunc(cfg)),
+		)
+		return listenAndServe(httpServer, cfg)
+	default:
+		return fmt.Errorf("unknown transport %q: must be one of stdio, sse, http", cfg.mode)
+	}
+}
+
+// listenAndServe mounts handler alongside a /metrics endpoint and serves the
+// combined mux, optionally over TLS. requireAuth rejects requests with a
+// missing/invalid bearer token with 401 before they reach handler; this is
+// the only point that can actually refuse a request, since the mcp-go
+// context funcs can only augment the context, not stop it.
+func listenAndServe(handler http.Handler, cfg transportConfig) error {
+	mux := http.NewServeMux()
+	mux.Handle("/", requireAuth(handler, cfg))
+	if cfg.metrics != nil {
+		mux.Handle("/metrics", cfg.metrics)
+	}
+
+	srv := &http.Server{Addr: cfg.listenAddr, Handler: mux}
+	if cfg.tlsCert != "" || cfg.tlsKey != "" {
+		return srv.ListenAndServeTLS(cfg.tlsCert, cfg.tlsKey)
+	}
+	return srv.ListenAndServe()
+}
+
+// requireAuth enforces cfg.authMode before handler ever sees the request. In
+// bearer mode it checks the caller's token against cfg.authSecret; in
+// per-request mode it just requires a non-empty bearer token be present, so
+// a caller that omits one can't silently fall back to the server's own
+// startup PAT. It is a no-op for any other auth mode.
+func requireAuth(handler http.Handler, cfg transportConfig) http.Handler {
+	switch cfg.authMode {
+	case "bearer":
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("Authorization")
+			token := strings.TrimPrefix(auth, "Bearer ")
+			if token == auth || cfg.authSecret == "" || token != cfg.authSecret {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		})
+	case "per-request":
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("Authorization")
+			token := strings.TrimPrefix(auth, "Bearer ")
+			if token == auth || token == "" {
+				http.Error(w, "unauthorized: missing bearer token", http.StatusUnauthorized)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		})
+	default:
+		return handler
+	}
+}
+
+// authContextFunc builds the per-request context function installed on the
+// HTTP/SSE servers. Auth enforcement happens earlier in requireAuth; this
+// only handles per-request mode, where it extracts the caller's own
+// DigitalOcean PAT from the Authorization header and stashes it on the
+// context so contextTokenTransport can scope the godo client to that user.
+func authContextFunc(cfg transportConfig) func(ctx context.Context, r *http.Request) context.Context {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if cfg.authMode != "per-request" {
+			return ctx
+		}
+
+		auth := r.Header.Get("Authorization")
+		token := strings.TrimPrefix(auth, "Bearer ")
+		if token == auth {
+			// no "Bearer " prefix present
+			return ctx
+		}
+
+		return context.WithValue(ctx, doTokenContextKey{}, token)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        transportConfig
+		authHeader string
+		wantStatus int
+	}{
+		{"no auth mode passes through", transportConfig{authMode: ""}, "", http.StatusOK},
+		{"bearer with correct secret", transportConfig{authMode: "bearer", authSecret: "s3cr3t"}, "Bearer s3cr3t", http.StatusOK},
+		{"bearer with wrong secret", transportConfig{authMode: "bearer", authSecret: "s3cr3t"}, "Bearer wrong", http.StatusUnauthorized},
+		{"bearer with missing header", transportConfig{authMode: "bearer", authSecret: "s3cr3t"}, "", http.StatusUnauthorized},
+		{"bearer with empty configured secret", transportConfig{authMode: "bearer", authSecret: ""}, "Bearer anything", http.StatusUnauthorized},
+		{"per-request with a token", transportConfig{authMode: "per-request"}, "Bearer user-pat", http.StatusOK},
+		{"per-request with missing header", transportConfig{authMode: "per-request"}, "", http.StatusUnauthorized},
+		{"per-request with empty bearer token", transportConfig{authMode: "per-request"}, "Bearer ", http.StatusUnauthorized},
+		{"per-request with non-bearer scheme", transportConfig{authMode: "per-request"}, "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := requireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}), tt.cfg)
+
+			req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAuthContextFunc(t *testing.T) {
+	t.Run("per-request stashes the caller's token", func(t *testing.T) {
+		cfg := transportConfig{authMode: "per-request"}
+		req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer user-pat")
+
+		ctx := authContextFunc(cfg)(context.Background(), req)
+
+		token, ok := tokenFromContext(ctx)
+		if !ok || token != "user-pat" {
+			t.Errorf("tokenFromContext() = %q, %v, want %q, true", token, ok, "user-pat")
+		}
+	})
+
+	t.Run("bearer mode never stashes a token", func(t *testing.T) {
+		cfg := transportConfig{authMode: "bearer", authSecret: "s3cr3t"}
+		req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer s3cr3t")
+
+		ctx := authContextFunc(cfg)(context.Background(), req)
+
+		if _, ok := tokenFromContext(ctx); ok {
+			t.Error("tokenFromContext() ok = true, want false in bearer mode")
+		}
+	})
+
+	t.Run("no bearer prefix leaves context untouched", func(t *testing.T) {
+		cfg := transportConfig{authMode: "per-request"}
+		req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+		req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+
+		ctx := authContextFunc(cfg)(context.Background(), req)
+
+		if _, ok := tokenFromContext(ctx); ok {
+			t.Error("tokenFromContext() ok = true, want false for a non-bearer scheme")
+		}
+	})
+}
+
+// recordingTransport captures the Authorization header it was called with.
+type recordingTransport struct {
+	gotAuthorization string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.gotAuthorization = req.Header.Get("Authorization")
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestContextTokenTransport(t *testing.T) {
+	t.Run("overrides Authorization with the per-request token", func(t *testing.T) {
+		base := &recordingTransport{}
+		rt := newContextTokenTransport(base)
+
+		req := httptest.NewRequest(http.MethodGet, "https://api.digitalocean.com/v2/droplets", nil)
+		req.Header.Set("Authorization", "Bearer startup-token")
+		ctx := context.WithValue(req.Context(), doTokenContextKey{}, "user-pat")
+
+		if _, err := rt.RoundTrip(req.WithContext(ctx)); err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		if want := "Bearer user-pat"; base.gotAuthorization != want {
+			t.Errorf("Authorization = %q, want %q", base.gotAuthorization, want)
+		}
+	})
+
+	t.Run("passes through unchanged with no context token", func(t *testing.T) {
+		base := &recordingTransport{}
+		rt := newContextTokenTransport(base)
+
+		req := httptest.NewRequest(http.MethodGet, "https://api.digitalocean.com/v2/droplets", nil)
+		req.Header.Set("Authorization", "Bearer startup-token")
+
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		if want := "Bearer startup-token"; base.gotAuthorization != want {
+			t.Errorf("Authorization = %q, want %q", base.gotAuthorization, want)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// userAgentTransport appends the calling MCP client's name/version (learned
+// from its `initialize` request) and an optional operator-supplied suffix to
+// the outgoing User-Agent, so DigitalOcean can attribute traffic per-assistant.
+type userAgentTransport struct {
+	base   http.RoundTripper
+	suffix string
+}
+
+func newUserAgentTransport(base http.RoundTripper, suffix string) *userAgentTransport {
+	return &userAgentTransport{base: base, suffix: strings.TrimSpace(suffix)}
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ua := req.UserAgent()
+
+	if session := server.ClientSessionFromContext(req.Context()); session != nil {
+		if info := session.Initialize().ClientInfo; info.Name != "" {
+			ua = fmt.Sprintf("%s %s", ua, uaToken(info.Name, info.Version))
+		}
+	}
+	if t.suffix != "" {
+		ua = fmt.Sprintf("%s %s", ua, t.suffix)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", ua)
+	return t.base.RoundTrip(req)
+}
+
+// uaToken renders a "name/version" User-Agent product token, replacing runs
+// of whitespace that would otherwise split it into multiple tokens with "-".
+func uaToken(name, version string) string {
+	name = strings.Join(strings.Fields(name), "-")
+	version = strings.Join(strings.Fields(version), "-")
+	if version == "" {
+		return name
+	}
+	return fmt.Sprintf("%s/%s", name, version)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUaToken(t *testing.T) {
+	tests := []struct {
+		name, version, want string
+	}{
+		{"claude-desktop", "1.2.3", "claude-desktop/1.2.3"},
+		{"claude-desktop", "", "claude-desktop"},
+		{"My Client", "1.0", "My-Client/1.0"},
+		{"My Client", "v1.0 beta", "My-Client/v1.0-beta"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.version, func(t *testing.T) {
+			if got := uaToken(tt.name, tt.version); got != tt.want {
+				t.Errorf("uaToken(%q, %q) = %q, want %q", tt.name, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserAgentTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		suffix string
+		want   string
+	}{
+		{"no suffix leaves the default User-Agent untouched", "", "go-client"},
+		{"suffix is appended", "my-deployment", "go-client my-deployment"},
+		{"surrounding whitespace on the suffix is trimmed", "  my-deployment  ", "go-client my-deployment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &recordingUserAgentTransport{}
+			rt := newUserAgentTransport(base, tt.suffix)
+
+			req := httptest.NewRequest(http.MethodGet, "https://api.digitalocean.com/v2/droplets", nil)
+			req.Header.Set("User-Agent", "go-client")
+
+			if _, err := rt.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			if base.gotUserAgent != tt.want {
+				t.Errorf("User-Agent = %q, want %q", base.gotUserAgent, tt.want)
+			}
+		})
+	}
+}
+
+// recordingUserAgentTransport captures the User-Agent header it was called
+// with; there is no MCP client session on the test context, so
+// newUserAgentTransport only has the base User-Agent and suffix to work with.
+type recordingUserAgentTransport struct {
+	gotUserAgent string
+}
+
+func (rt *recordingUserAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.gotUserAgent = req.Header.Get("User-Agent")
+	return httptest.NewRecorder().Result(), nil
+}
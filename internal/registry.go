@@ -0,0 +1,49 @@
+// Package registry wires up the MCP tool groups for each DigitalOcean
+// subsystem this server exposes, gating each group on the services list and
+// on whether the credentials it needs were actually provided.
+package registry
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/digitalocean/godo"
+	"github.com/mark3labs/mcp-go/server"
+
+	"mcp-digitalocean/internal/spaces"
+)
+
+// Clients bundles the upstream API clients available to tool registrars.
+// A field is nil when the credentials it needs were not configured; the
+// corresponding tool group is skipped rather than registered half-working.
+type Clients struct {
+	DO     *godo.Client
+	Spaces *spaces.Client
+}
+
+// Register wires up the MCP tools for each requested service against s. When
+// services is empty, every known service is registered, subject to having
+// the credentials it needs.
+func Register(logger *slog.Logger, s *server.MCPServer, clients Clients, services ...string) error {
+	want := func(name string) bool {
+		if len(services) == 0 {
+			return true
+		}
+		for _, svc := range services {
+			if svc == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	if want("spaces") {
+		if clients.Spaces == nil {
+			logger.Warn("DigitalOcean Spaces credentials not provided, skipping spaces tools. Use --spaces-access-id and --spaces-secret-key or the matching environment variables")
+		} else if err := spaces.RegisterTools(s, clients.Spaces); err != nil {
+			return fmt.Errorf("registering spaces tools: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package spaces provides an MCP tool group backed by DigitalOcean Spaces,
+// the account's S3-compatible object storage service.
+package spaces
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// DefaultEndpointTemplate is used when Config.EndpointTemplate is empty. It
+// is rendered per-region, following the DO Terraform provider's
+// CombinedConfig pattern for deriving a Spaces endpoint from a region name.
+const DefaultEndpointTemplate = "https://{{.Region}}.digitaloceanspaces.com"
+
+// Config holds the credentials and endpoint template needed to build Spaces
+// clients scoped to a single DigitalOcean region at a time.
+type Config struct {
+	AccessID         string
+	SecretKey        string
+	EndpointTemplate string
+}
+
+// Client builds region-scoped S3 clients against DigitalOcean Spaces.
+type Client struct {
+	cfg Config
+}
+
+// NewClient validates cfg and returns a Client. AccessID and SecretKey are
+// required; EndpointTemplate defaults to DefaultEndpointTemplate.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.AccessID == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("spaces: access id and secret key are required")
+	}
+	if cfg.EndpointTemplate == "" {
+		cfg.EndpointTemplate = DefaultEndpointTemplate
+	}
+	return &Client{cfg: cfg}, nil
+}
+
+// endpointFor renders the configured endpoint template for region.
+func (c *Client) endpointFor(region string) (string, error) {
+	tmpl, err := template.New("endpoint").Parse(c.cfg.EndpointTemplate)
+	if err != nil {
+		return "", fmt.Errorf("spaces: parsing endpoint template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Region string }{Region: region}); err != nil {
+		return "", fmt.Errorf("spaces: rendering endpoint template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// s3For returns an S3 service client scoped to region.
+func (c *Client) s3For(region string) (*s3.S3, error) {
+	endpoint, err := c.endpointFor(region)
+	if err != nil {
+		return nil, err
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials(c.cfg.AccessID, c.cfg.SecretKey, ""),
+		Endpoint:    aws.String(endpoint),
+		Region:      aws.String(region),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("spaces: creating session for region %q: %w", region, err)
+	}
+
+	return s3.New(sess), nil
+}
@@ -0,0 +1,260 @@
+package spaces
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RegisterTools adds the spaces_* tools to s, backed by client.
+func RegisterTools(s *server.MCPServer, client *Client) error {
+	s.AddTool(mcp.NewTool("spaces_list_buckets",
+		mcp.WithDescription("List the Spaces buckets visible from the given region's endpoint."),
+		mcp.WithString("region", mcp.Required(), mcp.Description("DigitalOcean region slug, e.g. nyc3")),
+	), client.listBuckets)
+
+	s.AddTool(mcp.NewTool("spaces_create_bucket",
+		mcp.WithDescription("Create a new Spaces bucket in the given region."),
+		mcp.WithString("region", mcp.Required(), mcp.Description("DigitalOcean region slug, e.g. nyc3")),
+		mcp.WithString("bucket", mcp.Required(), mcp.Description("Bucket name to create")),
+	), client.createBucket)
+
+	s.AddTool(mcp.NewTool("spaces_delete_bucket",
+		mcp.WithDescription("Delete an empty Spaces bucket."),
+		mcp.WithString("region", mcp.Required(), mcp.Description("DigitalOcean region slug, e.g. nyc3")),
+		mcp.WithString("bucket", mcp.Required(), mcp.Description("Bucket name to delete")),
+	), client.deleteBucket)
+
+	s.AddTool(mcp.NewTool("spaces_list_objects",
+		mcp.WithDescription("List objects in a Spaces bucket, optionally filtered by key prefix."),
+		mcp.WithString("region", mcp.Required(), mcp.Description("DigitalOcean region slug, e.g. nyc3")),
+		mcp.WithString("bucket", mcp.Required(), mcp.Description("Bucket to list")),
+		mcp.WithString("prefix", mcp.Description("Only list keys starting with this prefix")),
+	), client.listObjects)
+
+	s.AddTool(mcp.NewTool("spaces_put_object",
+		mcp.WithDescription("Upload an object to a Spaces bucket, from either a base64 payload or a local file path."),
+		mcp.WithString("region", mcp.Required(), mcp.Description("DigitalOcean region slug, e.g. nyc3")),
+		mcp.WithString("bucket", mcp.Required(), mcp.Description("Destination bucket")),
+		mcp.WithString("key", mcp.Required(), mcp.Description("Destination object key")),
+		mcp.WithString("content_base64", mcp.Description("Base64-encoded object payload; mutually exclusive with file_path")),
+		mcp.WithString("file_path", mcp.Description("Local file to upload; mutually exclusive with content_base64")),
+		mcp.WithString("content_type", mcp.Description("Optional MIME type to set on the object")),
+	), client.putObject)
+
+	s.AddTool(mcp.NewTool("spaces_get_object",
+		mcp.WithDescription("Download an object from a Spaces bucket as base64."),
+		mcp.WithString("region", mcp.Required(), mcp.Description("DigitalOcean region slug, e.g. nyc3")),
+		mcp.WithString("bucket", mcp.Required(), mcp.Description("Source bucket")),
+		mcp.WithString("key", mcp.Required(), mcp.Description("Object key to fetch")),
+	), client.getObject)
+
+	s.AddTool(mcp.NewTool("spaces_delete_object",
+		mcp.WithDescription("Delete an object from a Spaces bucket."),
+		mcp.WithString("region", mcp.Required(), mcp.Description("DigitalOcean region slug, e.g. nyc3")),
+		mcp.WithString("bucket", mcp.Required(), mcp.Description("Bucket containing the object")),
+		mcp.WithString("key", mcp.Required(), mcp.Description("Object key to delete")),
+	), client.deleteObject)
+
+	s.AddTool(mcp.NewTool("spaces_presign_url",
+		mcp.WithDescription("Generate a time-limited presigned URL for downloading an object."),
+		mcp.WithString("region", mcp.Required(), mcp.Description("DigitalOcean region slug, e.g. nyc3")),
+		mcp.WithString("bucket", mcp.Required(), mcp.Description("Bucket containing the object")),
+		mcp.WithString("key", mcp.Required(), mcp.Description("Object key to presign")),
+		mcp.WithString("expires_in", mcp.Description("Duration the URL stays valid, e.g. 15m (default 15m)")),
+	), client.presignURL)
+
+	return nil
+}
+
+func (c *Client) listBuckets(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	region := req.GetString("region", "")
+	svc, err := c.s3For(region)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	out, err := svc.ListBucketsWithContext(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("listing buckets: %s", err)), nil
+	}
+
+	names := make([]string, 0, len(out.Buckets))
+	for _, b := range out.Buckets {
+		names = append(names, aws.StringValue(b.Name))
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%v", names)), nil
+}
+
+func (c *Client) createBucket(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	region := req.GetString("region", "")
+	bucket := req.GetString("bucket", "")
+	svc, err := c.s3For(region)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if _, err := svc.CreateBucketWithContext(ctx, &s3.CreateBucketInput{Bucket: aws.String(bucket)}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("creating bucket %q: %s", bucket, err)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("created bucket %q in %q", bucket, region)), nil
+}
+
+func (c *Client) deleteBucket(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	region := req.GetString("region", "")
+	bucket := req.GetString("bucket", "")
+	svc, err := c.s3For(region)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if _, err := svc.DeleteBucketWithContext(ctx, &s3.DeleteBucketInput{Bucket: aws.String(bucket)}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("deleting bucket %q: %s", bucket, err)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("deleted bucket %q", bucket)), nil
+}
+
+func (c *Client) listObjects(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	region := req.GetString("region", "")
+	bucket := req.GetString("bucket", "")
+	prefix := req.GetString("prefix", "")
+	svc, err := c.s3For(region)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	in := &s3.ListObjectsV2Input{Bucket: aws.String(bucket)}
+	if prefix != "" {
+		in.Prefix = aws.String(prefix)
+	}
+
+	out, err := svc.ListObjectsV2WithContext(ctx, in)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("listing objects in %q: %s", bucket, err)), nil
+	}
+
+	keys := make([]string, 0, len(out.Contents))
+	for _, obj := range out.Contents {
+		keys = append(keys, aws.StringValue(obj.Key))
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%v", keys)), nil
+}
+
+func (c *Client) putObject(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	region := req.GetString("region", "")
+	bucket := req.GetString("bucket", "")
+	key := req.GetString("key", "")
+	contentBase64 := req.GetString("content_base64", "")
+	filePath := req.GetString("file_path", "")
+	contentType := req.GetString("content_type", "")
+
+	if (contentBase64 == "") == (filePath == "") {
+		return mcp.NewToolResultError("exactly one of content_base64 or file_path must be set"), nil
+	}
+
+	var body []byte
+	if filePath != "" {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("reading %q: %s", filePath, err)), nil
+		}
+		body = data
+	} else {
+		data, err := base64.StdEncoding.DecodeString(contentBase64)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("decoding content_base64: %s", err)), nil
+		}
+		body = data
+	}
+
+	svc, err := c.s3For(region)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	in := &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(body),
+	}
+	if contentType != "" {
+		in.ContentType = aws.String(contentType)
+	}
+
+	if _, err := svc.PutObjectWithContext(ctx, in); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("putting object %q: %s", key, err)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("uploaded %d bytes to %s/%s", len(body), bucket, key)), nil
+}
+
+func (c *Client) getObject(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	region := req.GetString("region", "")
+	bucket := req.GetString("bucket", "")
+	key := req.GetString("key", "")
+	svc, err := c.s3For(region)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	out, err := svc.GetObjectWithContext(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("getting object %q: %s", key, err)), nil
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("reading object %q: %s", key, err)), nil
+	}
+
+	return mcp.NewToolResultText(base64.StdEncoding.EncodeToString(data)), nil
+}
+
+func (c *Client) deleteObject(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	region := req.GetString("region", "")
+	bucket := req.GetString("bucket", "")
+	key := req.GetString("key", "")
+	svc, err := c.s3For(region)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if _, err := svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("deleting object %q: %s", key, err)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("deleted %s/%s", bucket, key)), nil
+}
+
+func (c *Client) presignURL(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	region := req.GetString("region", "")
+	bucket := req.GetString("bucket", "")
+	key := req.GetString("key", "")
+	expiresInStr := req.GetString("expires_in", "15m")
+
+	expiresIn, err := time.ParseDuration(expiresInStr)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("parsing expires_in %q: %s", expiresInStr, err)), nil
+	}
+
+	svc, err := c.s3For(region)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	presignReq, _ := svc.GetObjectRequest(&s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	url, err := presignReq.Presign(expiresIn)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("presigning %q: %s", key, err)), nil
+	}
+
+	return mcp.NewToolResultText(url), nil
+}
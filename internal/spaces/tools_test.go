@@ -0,0 +1,68 @@
+package spaces
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func putObjectRequest(args map[string]interface{}) mcp.CallToolRequest {
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	return req
+}
+
+func TestPutObjectContentExclusivity(t *testing.T) {
+	// Neither NewClient credentials nor a live S3 endpoint are needed: the
+	// content_base64/file_path exclusivity check runs before c.s3For is
+	// ever called.
+	c := &Client{}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "neither content_base64 nor file_path set",
+			args:    map[string]interface{}{"region": "nyc3", "bucket": "b", "key": "k"},
+			wantErr: "exactly one of content_base64 or file_path must be set",
+		},
+		{
+			name: "both content_base64 and file_path set",
+			args: map[string]interface{}{
+				"region": "nyc3", "bucket": "b", "key": "k",
+				"content_base64": "aGVsbG8=", "file_path": "/tmp/does-not-matter",
+			},
+			wantErr: "exactly one of content_base64 or file_path must be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := c.putObject(context.Background(), putObjectRequest(tt.args))
+			if err != nil {
+				t.Fatalf("putObject() error = %v, want nil (errors are reported via mcp.CallToolResult)", err)
+			}
+			if !result.IsError {
+				t.Fatalf("putObject() IsError = false, want true")
+			}
+			if got := toolResultText(result); !strings.Contains(got, tt.wantErr) {
+				t.Errorf("putObject() result = %q, want to contain %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+// toolResultText extracts the first text content block from result, mirroring
+// how mcp.NewToolResultError/mcp.NewToolResultText populate it.
+func toolResultText(result *mcp.CallToolResult) string {
+	for _, c := range result.Content {
+		if tc, ok := c.(mcp.TextContent); ok {
+			return tc.Text
+		}
+	}
+	return ""
+}